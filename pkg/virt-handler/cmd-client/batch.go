@@ -0,0 +1,97 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"context"
+	"sync"
+
+	k8sv1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/api/v1"
+)
+
+// maxConcurrentSyncs bounds how many virt-launcher sockets SyncAll talks
+// to at once, so a reconcile pass over a node running hundreds of VMIs
+// doesn't open hundreds of goroutines at the same instant.
+const maxConcurrentSyncs = 16
+
+// SyncResult is the outcome of syncing a single VM as part of a
+// BatchClient.SyncAll call.
+type SyncResult struct {
+	VM  *v1.VirtualMachine
+	Err error
+}
+
+// BatchClient syncs a set of VMs concurrently, each over its own cached,
+// already-dialed connection from a ClientCache. There is no server-side
+// batch RPC: every VM has its own virt-launcher socket, so the per-VM RPC
+// count can't be reduced below one Sync call per VM. What BatchClient and
+// ClientCache buy back is the dial and gRPC handshake, which previously
+// happened on every single reconcile pass, plus running those per-VM
+// calls concurrently instead of one at a time.
+type BatchClient struct {
+	cache *ClientCache
+}
+
+func NewBatchClient(cache *ClientCache) *BatchClient {
+	return &BatchClient{cache: cache}
+}
+
+// SyncAll syncs every vm in vms against its own cached LauncherClient
+// connection, fanning out up to maxConcurrentSyncs at a time. This is a
+// client-side concurrency helper, not a batched RPC - it still issues one
+// Sync call per VM. The returned slice has one SyncResult per input VM, in
+// the same order.
+func (b *BatchClient) SyncAll(ctx context.Context, baseDir string, vms []*v1.VirtualMachine, secrets map[string]map[string]*k8sv1.Secret) ([]SyncResult, error) {
+	results := make([]SyncResult, len(vms))
+
+	sem := make(chan struct{}, maxConcurrentSyncs)
+	var wg sync.WaitGroup
+
+	for i, vm := range vms {
+		i, vm := i, vm
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = SyncResult{VM: vm, Err: b.syncOne(ctx, baseDir, vm, secrets[vmKey(vm)])}
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func (b *BatchClient) syncOne(ctx context.Context, baseDir string, vm *v1.VirtualMachine, secrets map[string]*k8sv1.Secret) error {
+	socketPath := SocketFromNamespaceName(baseDir, vm.Namespace, vm.Name)
+
+	client, err := b.cache.Get(socketPath)
+	if err != nil {
+		return err
+	}
+
+	return client.SyncVirtualMachine(ctx, vm, secrets)
+}
+
+func vmKey(vm *v1.VirtualMachine) string {
+	return vm.Namespace + "/" + vm.Name
+}
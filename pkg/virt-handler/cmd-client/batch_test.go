@@ -0,0 +1,82 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/kubevirt/pkg/api/v1"
+)
+
+func TestBatchClientSyncAllFansOutPerVM(t *testing.T) {
+	cache := NewClientCache(10, time.Minute)
+
+	var mu sync.Mutex
+	bySocket := map[string]*fakeLauncherClient{}
+	cache.dial = func(socketPath string) (LauncherClient, error) {
+		c := newFakeLauncherClient()
+		mu.Lock()
+		bySocket[socketPath] = c
+		mu.Unlock()
+		return c, nil
+	}
+
+	const numVMs = 5
+	vms := make([]*v1.VirtualMachine, numVMs)
+	for i := range vms {
+		vms[i] = &v1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("vm%d", i)},
+		}
+	}
+
+	batch := NewBatchClient(cache)
+	results, err := batch.SyncAll(context.Background(), "/var/run/kubevirt", vms, nil)
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if len(results) != numVMs {
+		t.Fatalf("expected %d results, got %d", numVMs, len(results))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("vm %d: unexpected error %v", i, result.Err)
+		}
+		if result.VM != vms[i] {
+			t.Errorf("vm %d: result not in input order", i)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bySocket) != numVMs {
+		t.Fatalf("expected one virt-launcher socket per VM, got %d for %d VMs", len(bySocket), numVMs)
+	}
+	for socket, client := range bySocket {
+		if client.syncCallCount() != 1 {
+			t.Errorf("socket %s: expected exactly one Sync call, got %d", socket, client.syncCallCount())
+		}
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultCacheCapacity and DefaultIdleTimeout size the cache GetClient used
+// to dial a fresh connection per Sync call keeps instead, one entry per
+// virt-launcher socket on the node.
+const (
+	DefaultCacheCapacity = 500
+	DefaultIdleTimeout   = 5 * time.Minute
+)
+
+type cacheEntry struct {
+	socketPath string
+	client     LauncherClient
+	lastUsed   time.Time
+	elem       *list.Element
+}
+
+// dialCall tracks a dial that is already in flight for a socket path, so
+// concurrent Get calls for the same uncached socket wait on one dial
+// instead of each starting their own and racing to install the result.
+type dialCall struct {
+	done   chan struct{}
+	client LauncherClient
+	err    error
+}
+
+// ClientCache keeps one long-lived LauncherClient per virt-launcher socket
+// so reconcile loops stop paying a fresh dial-and-handshake for every Sync
+// call. Entries are evicted least-recently-used once capacity is exceeded,
+// or after sitting idle past idleTimeout.
+type ClientCache struct {
+	mu          sync.Mutex
+	entries     map[string]*cacheEntry
+	dialing     map[string]*dialCall
+	lru         *list.List
+	capacity    int
+	idleTimeout time.Duration
+
+	// dial is GetClient in production; tests substitute it to return a
+	// fake LauncherClient instead of dialing a real virt-launcher socket.
+	dial func(socketPath string) (LauncherClient, error)
+}
+
+// NewClientCache builds a ClientCache. capacity <= 0 and idleTimeout <= 0
+// fall back to DefaultCacheCapacity and DefaultIdleTimeout respectively.
+func NewClientCache(capacity int, idleTimeout time.Duration) *ClientCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+
+	return &ClientCache{
+		entries:     map[string]*cacheEntry{},
+		dialing:     map[string]*dialCall{},
+		lru:         list.New(),
+		capacity:    capacity,
+		idleTimeout: idleTimeout,
+		dial:        GetClient,
+	}
+}
+
+// Get returns the cached LauncherClient for socketPath, dialing and caching
+// one if none exists yet or the cached one has gone unhealthy. Concurrent
+// Get calls for the same uncached socketPath share a single dial: only the
+// first caller actually dials, and the rest wait on its result, so
+// concurrent dials never race to install and close one another's
+// connections. Replacing an unhealthy entry, and LRU or idle eviction,
+// close the evicted client even if an earlier Get returned it.
+func (c *ClientCache) Get(socketPath string) (LauncherClient, error) {
+	c.mu.Lock()
+	c.evictIdleLocked()
+
+	if entry, ok := c.entries[socketPath]; ok && entry.client.Health().Healthy {
+		entry.lastUsed = time.Now()
+		c.lru.MoveToFront(entry.elem)
+		client := entry.client
+		c.mu.Unlock()
+		return client, nil
+	}
+
+	if call, ok := c.dialing[socketPath]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.client, call.err
+	}
+
+	call := &dialCall{done: make(chan struct{})}
+	c.dialing[socketPath] = call
+	c.mu.Unlock()
+
+	call.client, call.err = c.dial(socketPath)
+
+	c.mu.Lock()
+	delete(c.dialing, socketPath)
+	if call.err == nil {
+		if old, ok := c.entries[socketPath]; ok {
+			c.lru.Remove(old.elem)
+			old.client.Close()
+		}
+
+		entry := &cacheEntry{socketPath: socketPath, client: call.client, lastUsed: time.Now()}
+		entry.elem = c.lru.PushFront(entry)
+		c.entries[socketPath] = entry
+
+		c.evictOverCapacityLocked()
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+	return call.client, call.err
+}
+
+// Evict closes and removes the cached client for socketPath, if any.
+func (c *ClientCache) Evict(socketPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[socketPath]
+	if !ok {
+		return
+	}
+	c.lru.Remove(entry.elem)
+	delete(c.entries, socketPath)
+	entry.client.Close()
+}
+
+// Close evicts and closes every cached client.
+func (c *ClientCache) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, entry := range c.entries {
+		entry.client.Close()
+	}
+	c.entries = map[string]*cacheEntry{}
+	c.lru.Init()
+}
+
+func (c *ClientCache) evictOverCapacityLocked() {
+	for len(c.entries) > c.capacity {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			return
+		}
+		entry := oldest.Value.(*cacheEntry)
+		c.lru.Remove(oldest)
+		delete(c.entries, entry.socketPath)
+		entry.client.Close()
+	}
+}
+
+func (c *ClientCache) evictIdleLocked() {
+	now := time.Now()
+	for elem := c.lru.Back(); elem != nil; {
+		entry := elem.Value.(*cacheEntry)
+		if now.Sub(entry.lastUsed) < c.idleTimeout {
+			break
+		}
+		prev := elem.Prev()
+		c.lru.Remove(elem)
+		delete(c.entries, entry.socketPath)
+		entry.client.Close()
+		elem = prev
+	}
+}
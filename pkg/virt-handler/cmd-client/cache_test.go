@@ -0,0 +1,120 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientCacheGetDedupsConcurrentDials(t *testing.T) {
+	cache := NewClientCache(10, time.Minute)
+
+	var dialCount int32
+	cache.dial = func(socketPath string) (LauncherClient, error) {
+		atomic.AddInt32(&dialCount, 1)
+		time.Sleep(20 * time.Millisecond)
+		return newFakeLauncherClient(), nil
+	}
+
+	const concurrency = 10
+	clients := make([]LauncherClient, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client, err := cache.Get("/var/run/kubevirt/sockets/default_vm1_sock")
+			if err != nil {
+				t.Errorf("Get failed: %v", err)
+				return
+			}
+			clients[i] = client
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&dialCount); got != 1 {
+		t.Fatalf("expected exactly one dial for concurrent Gets of the same socket, got %d", got)
+	}
+	for i := 1; i < concurrency; i++ {
+		if clients[i] != clients[0] {
+			t.Fatalf("expected every concurrent Get to return the same cached client")
+		}
+	}
+}
+
+func TestClientCacheEvictsOverCapacity(t *testing.T) {
+	cache := NewClientCache(2, time.Minute)
+
+	var created []*fakeLauncherClient
+	cache.dial = func(socketPath string) (LauncherClient, error) {
+		c := newFakeLauncherClient()
+		created = append(created, c)
+		return c, nil
+	}
+
+	for _, socket := range []string{"sock-a", "sock-b", "sock-c"} {
+		if _, err := cache.Get(socket); err != nil {
+			t.Fatalf("Get(%q) failed: %v", socket, err)
+		}
+	}
+
+	if !created[0].isClosed() {
+		t.Fatalf("expected the least-recently-used entry to be evicted and closed")
+	}
+	if created[1].isClosed() || created[2].isClosed() {
+		t.Fatalf("did not expect the two most recently used entries to be evicted")
+	}
+}
+
+func TestClientCacheEvictsIdleEntries(t *testing.T) {
+	cache := NewClientCache(10, 10*time.Millisecond)
+
+	var client *fakeLauncherClient
+	cache.dial = func(socketPath string) (LauncherClient, error) {
+		client = newFakeLauncherClient()
+		return client, nil
+	}
+
+	if _, err := cache.Get("sock-a"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	var redialed bool
+	cache.dial = func(socketPath string) (LauncherClient, error) {
+		redialed = true
+		return newFakeLauncherClient(), nil
+	}
+	if _, err := cache.Get("sock-a"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if !client.isClosed() {
+		t.Fatalf("expected idle entry to be closed on eviction")
+	}
+	if !redialed {
+		t.Fatalf("expected Get to redial after the cached entry went idle")
+	}
+}
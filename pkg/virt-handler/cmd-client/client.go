@@ -26,50 +26,65 @@ package cmdclient
 */
 
 import (
+	"context"
+	"encoding/json"
 	goerror "errors"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"net/rpc"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	k8sv1 "k8s.io/api/core/v1"
 
 	"kubevirt.io/kubevirt/pkg/api/v1"
 	diskutils "kubevirt.io/kubevirt/pkg/ephemeral-disk-utils"
+	cmdv1 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 )
 
-type Reply struct {
-	Success bool
-	Message string
-	Domain  *api.Domain
-}
-
-type Args struct {
-	// used for domain management
-	VM        *v1.VirtualMachine
-	K8Secrets map[string]*k8sv1.Secret
+// dialTimeout bounds how long GetClient waits for the per-VM Unix socket to
+// accept the gRPC handshake.
+const dialTimeout = 5 * time.Second
 
-	// used for syncing secrets
-	SecretUsageType string
-	SecretUsageID   string
-	SecretValue     string
-}
+// DomainEvent mirrors cmdv1.DomainEvent so callers of WatchDomainEvents
+// don't have to import the generated package directly.
+type DomainEvent = cmdv1.DomainEvent
 
 type LauncherClient interface {
-	SyncVirtualMachine(vm *v1.VirtualMachine, secrets map[string]*k8sv1.Secret) error
-	ShutdownVirtualMachine(vm *v1.VirtualMachine) error
-	KillVirtualMachine(vm *v1.VirtualMachine) error
-	SyncSecret(vm *v1.VirtualMachine, usageType string, usageID string, secretValue string) error
-	GetDomain() (*api.Domain, bool, error)
-	Ping() error
+	SyncVirtualMachine(ctx context.Context, vm *v1.VirtualMachine, secrets map[string]*k8sv1.Secret) error
+	ShutdownVirtualMachine(ctx context.Context, vm *v1.VirtualMachine) error
+	KillVirtualMachine(ctx context.Context, vm *v1.VirtualMachine) error
+	SyncSecret(ctx context.Context, vm *v1.VirtualMachine, usageType string, usageID string, secretValue string) error
+	GetDomain(ctx context.Context) (*api.Domain, bool, error)
+	Ping(ctx context.Context) error
+	// WatchDomainEvents streams libvirt lifecycle events for the domain
+	// until the passed context is cancelled.
+	WatchDomainEvents(ctx context.Context) (cmdv1.Launcher_WatchDomainEventsClient, error)
+	// Health reports the result of the background Ping health check, so
+	// callers can tell a reachable-but-failing VM apart from one whose
+	// connection is simply down.
+	Health() HealthStatus
 	Close()
 }
 
 type VirtLauncherClient struct {
-	client *rpc.Client
+	target string
+	opts   ClientOptions
+
+	connMu    sync.RWMutex
+	v1client  cmdv1.LauncherClient
+	conn      RPCConn
+	transport Transport
+
+	healthMu   sync.RWMutex
+	health     HealthStatus
+	stopHealth chan struct{}
+	closeOnce  sync.Once
 }
 
 func ListAllSockets(baseDir string) ([]string, error) {
@@ -116,115 +131,341 @@ func DomainFromSocketPath(socketPath string) (*api.Domain, error) {
 	return domain, nil
 }
 
+// GetClient dials the per-VM Unix socket served by virt-launcher and
+// returns a gRPC-backed LauncherClient with the default reconnect and
+// health-check behavior.
 func GetClient(socketPath string) (LauncherClient, error) {
-	conn, err := rpc.Dial("unix", socketPath)
+	return NewClient(NewUnixSocketTransport(), socketPath, DefaultClientOptions())
+}
+
+// NewClient dials target through transport and returns a LauncherClient
+// backed by the resulting connection. Production code reaches this through
+// GetClient; tests and the SSH-tunneled debug path construct their own
+// Transport and call NewClient directly.
+func NewClient(transport Transport, target string, opts ClientOptions) (LauncherClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	conn, err := transport.Dial(ctx, target)
 	if err != nil {
 		return nil, err
 	}
 
-	return &VirtLauncherClient{client: conn}, nil
+	c := &VirtLauncherClient{
+		target:     target,
+		opts:       opts,
+		v1client:   cmdv1.NewLauncherClient(conn),
+		conn:       conn,
+		transport:  transport,
+		health:     HealthStatus{Healthy: true},
+		stopHealth: make(chan struct{}),
+	}
+
+	go c.healthLoop()
+
+	return c, nil
 }
 
 func (c *VirtLauncherClient) Close() {
-	c.client.Close()
+	c.closeOnce.Do(func() {
+		close(c.stopHealth)
+		c.connMu.RLock()
+		defer c.connMu.RUnlock()
+		c.conn.Close()
+		c.transport.Close()
+	})
 }
 
-func (c *VirtLauncherClient) genericSendCmd(args *Args, cmd string) (*Reply, error) {
-	reply := &Reply{}
+func (c *VirtLauncherClient) Health() HealthStatus {
+	c.healthMu.RLock()
+	defer c.healthMu.RUnlock()
+	return c.health
+}
 
-	err := c.client.Call(cmd, args, reply)
-	if IsDisconnected(err) {
-		return reply, err
-	} else if err != nil {
-		msg := fmt.Sprintf("unknown error encountered sending command %s: %s", cmd, err.Error())
-		return reply, fmt.Errorf(msg)
-	} else if reply.Success != true {
-		msg := fmt.Sprintf("server error. command %s failed: %s", cmd, reply.Message)
-		return reply, fmt.Errorf(msg)
+func (c *VirtLauncherClient) healthLoop() {
+	ticker := time.NewTicker(c.opts.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopHealth:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), c.opts.CallTimeout)
+			err := c.rawPing(ctx)
+			if err != nil && IsDisconnected(ctx, err) {
+				c.reconnect()
+			}
+			cancel()
+			c.recordResult(err)
+		}
+	}
+}
+
+func (c *VirtLauncherClient) recordResult(err error) {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+
+	c.health.LastChecked = time.Now()
+	if err == nil {
+		c.health.ConsecutiveFailures = 0
+		c.health.LastError = nil
+		c.health.Healthy = true
+		return
 	}
-	return reply, nil
+
+	c.health.ConsecutiveFailures++
+	c.health.LastError = err
+	if c.health.ConsecutiveFailures >= c.opts.MaxConsecutiveFailures {
+		c.health.Healthy = false
+	}
+}
+
+func (c *VirtLauncherClient) checkAvailable() error {
+	c.healthMu.RLock()
+	defer c.healthMu.RUnlock()
+	if !c.health.Healthy {
+		return &ErrUnavailable{Target: c.target, ConsecutiveFailures: c.health.ConsecutiveFailures}
+	}
+	return nil
 }
 
-func (c *VirtLauncherClient) ShutdownVirtualMachine(vm *v1.VirtualMachine) error {
-	cmd := "Launcher.Shutdown"
+// withDeadline enforces opts.CallTimeout on ctx unless the caller already
+// set a deadline of its own.
+func (c *VirtLauncherClient) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.opts.CallTimeout)
+}
 
-	args := &Args{
-		VM: vm,
+// reconnect re-dials the transport with capped exponential backoff,
+// swapping in the new connection once it succeeds.
+func (c *VirtLauncherClient) reconnect() error {
+	backoff := c.opts.InitialReconnectBackoff
+	var lastErr error
+
+	for attempt := 0; attempt < c.opts.MaxReconnectAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		conn, err := c.transport.Dial(ctx, c.target)
+		cancel()
+
+		if err == nil {
+			c.connMu.Lock()
+			c.conn.Close()
+			c.conn = conn
+			c.v1client = cmdv1.NewLauncherClient(conn)
+			c.connMu.Unlock()
+			rpcReconnectsTotal.Inc()
+			return nil
+		}
+
+		lastErr = err
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > c.opts.MaxReconnectBackoff {
+			backoff = c.opts.MaxReconnectBackoff
+		}
 	}
-	_, err := c.genericSendCmd(args, cmd)
 
-	return err
+	return fmt.Errorf("failed to reconnect to %s after %d attempts: %v", c.target, c.opts.MaxReconnectAttempts, lastErr)
 }
 
-func (c *VirtLauncherClient) KillVirtualMachine(vm *v1.VirtualMachine) error {
-	cmd := "Launcher.Kill"
+// call runs fn with a deadline-bound context, and on mid-call disconnect
+// re-dials the socket. Idempotent commands are retried once against the
+// fresh connection; non-idempotent commands instead surface a
+// PossiblyAppliedError, since the caller can't tell whether virt-launcher
+// applied them before the connection dropped.
+func (c *VirtLauncherClient) call(ctx context.Context, idempotent bool, cmdName string, fn func(context.Context) error) error {
+	if err := c.checkAvailable(); err != nil {
+		return err
+	}
+
+	rpcInflight.Inc()
+	start := time.Now()
+	callCtx, cancel := c.withDeadline(ctx)
+	err := fn(callCtx)
+	disconnected := IsDisconnected(callCtx, err)
+	cancel()
+	rpcDuration.WithLabelValues(cmdName).Observe(time.Since(start).Seconds())
+	rpcInflight.Dec()
+
+	if !disconnected {
+		return err
+	}
+
+	if reconnectErr := c.reconnect(); reconnectErr != nil {
+		return reconnectErr
+	}
 
-	args := &Args{
-		VM: vm,
+	if !idempotent {
+		return &PossiblyAppliedError{Cmd: cmdName, Err: err}
 	}
-	_, err := c.genericSendCmd(args, cmd)
 
-	return err
+	retryCtx, retryCancel := c.withDeadline(ctx)
+	defer retryCancel()
+	return fn(retryCtx)
+}
+
+func handleError(ctx context.Context, err error, cmdName string, response *cmdv1.Response) error {
+	if IsDisconnected(ctx, err) {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("unknown error encountered sending command %s: %s", cmdName, err.Error())
+	} else if response != nil && !response.Success {
+		return fmt.Errorf("server error. command %s failed: %s", cmdName, response.Message)
+	}
+	return nil
 }
 
-func (c *VirtLauncherClient) GetDomain() (*api.Domain, bool, error) {
+func (c *VirtLauncherClient) v1Client() cmdv1.LauncherClient {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.v1client
+}
+
+func (c *VirtLauncherClient) ShutdownVirtualMachine(ctx context.Context, vm *v1.VirtualMachine) error {
+	vmJSON, err := json.Marshal(vm)
+	if err != nil {
+		return err
+	}
+
+	return c.call(ctx, false, "Shutdown", func(ctx context.Context) error {
+		response, err := c.v1Client().Shutdown(ctx, &cmdv1.VMRequest{Vm: &cmdv1.VM{VmJson: vmJSON}})
+		return handleError(ctx, err, "Shutdown", response)
+	})
+}
+
+func (c *VirtLauncherClient) KillVirtualMachine(ctx context.Context, vm *v1.VirtualMachine) error {
+	vmJSON, err := json.Marshal(vm)
+	if err != nil {
+		return err
+	}
+
+	return c.call(ctx, false, "Kill", func(ctx context.Context) error {
+		response, err := c.v1Client().Kill(ctx, &cmdv1.VMRequest{Vm: &cmdv1.VM{VmJson: vmJSON}})
+		return handleError(ctx, err, "Kill", response)
+	})
+}
+
+func (c *VirtLauncherClient) GetDomain(ctx context.Context) (*api.Domain, bool, error) {
 	domain := &api.Domain{}
-	cmd := "Launcher.GetDomain"
 	exists := false
 
-	args := &Args{}
+	err := c.call(ctx, true, "GetDomain", func(ctx context.Context) error {
+		response, err := c.v1Client().GetDomain(ctx, &cmdv1.EmptyRequest{})
+		if err != nil {
+			return handleError(ctx, err, "GetDomain", nil)
+		}
+		if err := handleError(ctx, nil, "GetDomain", response.Response); err != nil {
+			return err
+		}
+
+		if len(response.DomainJson) > 0 {
+			if err := json.Unmarshal(response.DomainJson, domain); err != nil {
+				return err
+			}
+			exists = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
 
-	reply, err := c.genericSendCmd(args, cmd)
+	return domain, exists, nil
+}
+
+func (c *VirtLauncherClient) SyncVirtualMachine(ctx context.Context, vm *v1.VirtualMachine, secrets map[string]*k8sv1.Secret) error {
+	vmJSON, err := json.Marshal(vm)
 	if err != nil {
-		return nil, exists, err
+		return err
 	}
 
-	if reply.Domain != nil {
-		domain = reply.Domain
-		exists = true
+	secretsJSON := make(map[string][]byte, len(secrets))
+	for key, secret := range secrets {
+		encoded, err := json.Marshal(secret)
+		if err != nil {
+			return err
+		}
+		secretsJSON[key] = encoded
 	}
-	return domain, exists, nil
 
+	return c.call(ctx, false, "Sync", func(ctx context.Context) error {
+		response, err := c.v1Client().Sync(ctx, &cmdv1.SyncRequest{
+			Vm:      &cmdv1.VM{VmJson: vmJSON},
+			Secrets: secretsJSON,
+		})
+		return handleError(ctx, err, "Sync", response)
+	})
 }
-func (c *VirtLauncherClient) SyncVirtualMachine(vm *v1.VirtualMachine, secrets map[string]*k8sv1.Secret) error {
 
-	cmd := "Launcher.Sync"
-
-	args := &Args{
-		VM:        vm,
-		K8Secrets: secrets,
+func (c *VirtLauncherClient) SyncSecret(ctx context.Context, vm *v1.VirtualMachine, usageType string, usageID string, secretValue string) error {
+	vmJSON, err := json.Marshal(vm)
+	if err != nil {
+		return err
 	}
 
-	_, err := c.genericSendCmd(args, cmd)
+	return c.call(ctx, false, "SyncSecret", func(ctx context.Context) error {
+		response, err := c.v1Client().SyncSecret(ctx, &cmdv1.SyncSecretRequest{
+			Vm:              &cmdv1.VM{VmJson: vmJSON},
+			SecretUsageType: usageType,
+			SecretUsageId:   usageID,
+			SecretValue:     secretValue,
+		})
+		return handleError(ctx, err, "SyncSecret", response)
+	})
+}
 
-	return err
+// rawPing calls Launcher.Ping directly, bypassing the health gate. It backs
+// both the public Ping method and the background health loop, which must
+// be able to probe the connection even while the client is marked
+// unhealthy.
+func (c *VirtLauncherClient) rawPing(ctx context.Context) error {
+	response, err := c.v1Client().Ping(ctx, &cmdv1.EmptyRequest{})
+	return handleError(ctx, err, "Ping", response)
 }
 
-func (c *VirtLauncherClient) SyncSecret(vm *v1.VirtualMachine, usageType string, usageID string, secretValue string) error {
-	cmd := "Launcher.SyncSecret"
+func (c *VirtLauncherClient) Ping(ctx context.Context) error {
+	return c.call(ctx, true, "Ping", c.rawPing)
+}
 
-	args := &Args{
-		VM:              vm,
-		SecretUsageType: usageType,
-		SecretUsageID:   usageID,
-		SecretValue:     secretValue,
+func (c *VirtLauncherClient) WatchDomainEvents(ctx context.Context) (cmdv1.Launcher_WatchDomainEventsClient, error) {
+	if err := c.checkAvailable(); err != nil {
+		return nil, err
 	}
 
-	_, err := c.genericSendCmd(args, cmd)
-	return err
+	stream, err := c.v1Client().WatchDomainEvents(ctx, &cmdv1.EmptyRequest{})
+	if err != nil {
+		return nil, handleError(ctx, err, "WatchDomainEvents", nil)
+	}
+	return stream, nil
 }
 
-func IsDisconnected(err error) bool {
-	if err == rpc.ErrShutdown || err == io.ErrUnexpectedEOF || err == io.EOF {
+// IsDisconnected reports whether err indicates the gRPC connection to
+// virt-launcher is gone, as opposed to a normal application-level failure.
+//
+// callCtx is the context the failing call was made with. A unary RPC
+// returns codes.Canceled whenever callCtx itself is canceled, which has
+// nothing to do with the transport - a reconcile worker abandoning a
+// stale work item looks identical on the wire to the peer disappearing.
+// Only treat codes.Canceled as a disconnect when the caller's own context
+// is still live, i.e. the cancellation came from the server or transport.
+func IsDisconnected(callCtx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.Unavailable:
 		return true
+	case codes.Canceled:
+		return callCtx.Err() == nil
 	}
 	return false
 }
-
-func (c *VirtLauncherClient) Ping() error {
-	cmd := "Launcher.Ping"
-	args := &Args{}
-	_, err := c.genericSendCmd(args, cmd)
-
-	return err
-}
\ No newline at end of file
@@ -0,0 +1,120 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "kubevirt.io/kubevirt/pkg/api/v1"
+)
+
+func newTestClient(t *testing.T, srv *fakeServer) LauncherClient {
+	t.Helper()
+
+	transport := NewInProcessTransport(srv)
+	t.Cleanup(transport.Close)
+
+	client, err := NewClient(transport, "ignored", DefaultClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestVirtLauncherClientRPCMethods(t *testing.T) {
+	client := newTestClient(t, &fakeServer{})
+	vm := &v1.VirtualMachine{}
+	ctx := context.Background()
+
+	if err := client.ShutdownVirtualMachine(ctx, vm); err != nil {
+		t.Errorf("ShutdownVirtualMachine failed: %v", err)
+	}
+	if err := client.KillVirtualMachine(ctx, vm); err != nil {
+		t.Errorf("KillVirtualMachine failed: %v", err)
+	}
+	if err := client.SyncSecret(ctx, vm, "usageType", "usageID", "secret"); err != nil {
+		t.Errorf("SyncSecret failed: %v", err)
+	}
+	if _, exists, err := client.GetDomain(ctx); err != nil || exists {
+		t.Errorf("GetDomain() = exists %v, err %v, want exists false, err nil", exists, err)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.WatchDomainEvents(streamCtx)
+	if err != nil {
+		t.Fatalf("WatchDomainEvents failed: %v", err)
+	}
+	cancel()
+	if _, err := stream.Recv(); err == nil {
+		t.Errorf("expected Recv to fail once the watch context is canceled")
+	}
+}
+
+func TestSyncVirtualMachineReturnsPossiblyAppliedOnDisconnect(t *testing.T) {
+	srv := &fakeServer{syncFailuresLeft: 1}
+	client := newTestClient(t, srv)
+	vm := &v1.VirtualMachine{}
+
+	err := client.SyncVirtualMachine(context.Background(), vm, nil)
+	if !IsPossiblyApplied(err) {
+		t.Fatalf("expected a PossiblyAppliedError from a Sync that failed mid-connection, got %v", err)
+	}
+
+	// The client reconnects but does not retry non-idempotent commands
+	// internally; a fresh call against the now-healthy server must succeed.
+	if err := client.SyncVirtualMachine(context.Background(), vm, nil); err != nil {
+		t.Fatalf("expected SyncVirtualMachine to succeed after reconnect, got %v", err)
+	}
+}
+
+func TestIsDisconnectedDistinguishesCallerCancellation(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "peer gone")
+	canceledByPeer := status.Error(codes.Canceled, "peer canceled")
+
+	liveCtx := context.Background()
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"unavailable is always a disconnect", liveCtx, unavailable, true},
+		{"canceled with a live caller ctx is a peer/transport disconnect", liveCtx, canceledByPeer, true},
+		{"canceled with an already-canceled caller ctx is just local cancellation", canceledCtx, canceledByPeer, false},
+		{"no error", liveCtx, nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IsDisconnected(c.ctx, c.err); got != c.want {
+				t.Errorf("IsDisconnected() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
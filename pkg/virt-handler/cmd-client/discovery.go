@@ -0,0 +1,70 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+//go:generate mockgen -source $GOFILE -package=$GOPACKAGE -destination=generated_mock_$GOFILE
+
+import (
+	"path/filepath"
+)
+
+// Discovery locates the per-VM Unix sockets served by virt-launcher pods on
+// a node. Tests can substitute an in-memory implementation instead of
+// touching the real filesystem.
+type Discovery interface {
+	ListAllSockets(baseDir string) ([]string, error)
+	SocketFromNamespaceName(baseDir, namespace, name string) string
+}
+
+// FileDiscovery implements Discovery against the real filesystem layout
+// used by virt-handler, i.e. <baseDir>/sockets/<namespace>_<name>_sock.
+type FileDiscovery struct{}
+
+func NewFileDiscovery() Discovery {
+	return &FileDiscovery{}
+}
+
+func (d *FileDiscovery) ListAllSockets(baseDir string) ([]string, error) {
+	return ListAllSockets(baseDir)
+}
+
+func (d *FileDiscovery) SocketFromNamespaceName(baseDir, namespace, name string) string {
+	return SocketFromNamespaceName(baseDir, namespace, name)
+}
+
+// MemoryDiscovery is a Discovery backed by an in-memory map, for unit tests
+// that want to control which sockets "exist" without touching disk.
+type MemoryDiscovery struct {
+	// Sockets maps a baseDir to the socket paths ListAllSockets should
+	// report for it.
+	Sockets map[string][]string
+}
+
+func NewMemoryDiscovery() *MemoryDiscovery {
+	return &MemoryDiscovery{Sockets: map[string][]string{}}
+}
+
+func (d *MemoryDiscovery) ListAllSockets(baseDir string) ([]string, error) {
+	return d.Sockets[baseDir], nil
+}
+
+func (d *MemoryDiscovery) SocketFromNamespaceName(baseDir, namespace, name string) string {
+	return filepath.Join(SocketsDirectory(baseDir), namespace+"_"+name+"_sock")
+}
@@ -0,0 +1,70 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	goerrors "errors"
+	"fmt"
+)
+
+// ErrUnavailable is returned by every LauncherClient method once the
+// background health check has seen MaxConsecutiveFailures in a row. It
+// tells callers to treat the virt-launcher pod as unreachable rather than
+// retrying the same call.
+type ErrUnavailable struct {
+	Target              string
+	ConsecutiveFailures int
+}
+
+func (e *ErrUnavailable) Error() string {
+	return fmt.Sprintf("launcher client for %s is unavailable after %d consecutive health check failures", e.Target, e.ConsecutiveFailures)
+}
+
+// PossiblyAppliedError wraps a non-idempotent command (Sync, SyncSecret,
+// Kill, Shutdown) failure that occurred after the connection dropped mid-call.
+// The caller cannot tell whether virt-launcher applied the command before
+// the socket went away, so it must be treated as possibly-applied rather
+// than a clean failure.
+type PossiblyAppliedError struct {
+	Cmd string
+	Err error
+}
+
+func (e *PossiblyAppliedError) Error() string {
+	return fmt.Sprintf("command %s may or may not have been applied before the connection was lost: %v", e.Cmd, e.Err)
+}
+
+func (e *PossiblyAppliedError) Unwrap() error {
+	return e.Err
+}
+
+// IsPossiblyApplied reports whether err indicates a non-idempotent command
+// was in flight when the connection to virt-launcher dropped.
+func IsPossiblyApplied(err error) bool {
+	var possiblyApplied *PossiblyAppliedError
+	return goerrors.As(err, &possiblyApplied)
+}
+
+// IsUnavailable reports whether err is an ErrUnavailable, i.e. the client
+// refused to even attempt the call because the health check is failing.
+func IsUnavailable(err error) bool {
+	var unavailable *ErrUnavailable
+	return goerrors.As(err, &unavailable)
+}
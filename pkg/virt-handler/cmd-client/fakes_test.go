@@ -0,0 +1,152 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	k8sv1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/api/v1"
+	cmdv1 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+// fakeServer is a cmdv1.LauncherServer used to drive a real
+// VirtLauncherClient over an InProcessTransport without a virt-launcher
+// process or Unix socket. pingFailuresLeft and syncFailuresLeft let tests
+// inject a run of failing calls before the server starts responding
+// normally again, to exercise the reconnect/health-check logic in
+// client.go.
+type fakeServer struct {
+	pingFailuresLeft int32
+	syncFailuresLeft int32
+}
+
+func (s *fakeServer) Sync(ctx context.Context, in *cmdv1.SyncRequest) (*cmdv1.Response, error) {
+	if atomic.AddInt32(&s.syncFailuresLeft, -1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "injected failure")
+	}
+	atomic.AddInt32(&s.syncFailuresLeft, 1)
+	return &cmdv1.Response{Success: true}, nil
+}
+
+func (s *fakeServer) Shutdown(ctx context.Context, in *cmdv1.VMRequest) (*cmdv1.Response, error) {
+	return &cmdv1.Response{Success: true}, nil
+}
+
+func (s *fakeServer) Kill(ctx context.Context, in *cmdv1.VMRequest) (*cmdv1.Response, error) {
+	return &cmdv1.Response{Success: true}, nil
+}
+
+func (s *fakeServer) SyncSecret(ctx context.Context, in *cmdv1.SyncSecretRequest) (*cmdv1.Response, error) {
+	return &cmdv1.Response{Success: true}, nil
+}
+
+func (s *fakeServer) GetDomain(ctx context.Context, in *cmdv1.EmptyRequest) (*cmdv1.DomainResponse, error) {
+	return &cmdv1.DomainResponse{Response: &cmdv1.Response{Success: true}}, nil
+}
+
+func (s *fakeServer) Ping(ctx context.Context, in *cmdv1.EmptyRequest) (*cmdv1.Response, error) {
+	if atomic.AddInt32(&s.pingFailuresLeft, -1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "injected failure")
+	}
+	atomic.AddInt32(&s.pingFailuresLeft, 1)
+	return &cmdv1.Response{Success: true}, nil
+}
+
+func (s *fakeServer) WatchDomainEvents(in *cmdv1.EmptyRequest, stream cmdv1.Launcher_WatchDomainEventsServer) error {
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+// fakeLauncherClient is a LauncherClient test double for exercising
+// ClientCache and BatchClient without dialing any real transport.
+type fakeLauncherClient struct {
+	mu        sync.Mutex
+	closed    bool
+	healthy   bool
+	syncCalls int
+	syncErr   error
+}
+
+func newFakeLauncherClient() *fakeLauncherClient {
+	return &fakeLauncherClient{healthy: true}
+}
+
+func (f *fakeLauncherClient) SyncVirtualMachine(ctx context.Context, vm *v1.VirtualMachine, secrets map[string]*k8sv1.Secret) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.syncCalls++
+	return f.syncErr
+}
+
+func (f *fakeLauncherClient) ShutdownVirtualMachine(ctx context.Context, vm *v1.VirtualMachine) error {
+	return nil
+}
+
+func (f *fakeLauncherClient) KillVirtualMachine(ctx context.Context, vm *v1.VirtualMachine) error {
+	return nil
+}
+
+func (f *fakeLauncherClient) SyncSecret(ctx context.Context, vm *v1.VirtualMachine, usageType string, usageID string, secretValue string) error {
+	return nil
+}
+
+func (f *fakeLauncherClient) GetDomain(ctx context.Context) (*api.Domain, bool, error) {
+	return nil, false, nil
+}
+
+func (f *fakeLauncherClient) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakeLauncherClient) WatchDomainEvents(ctx context.Context) (cmdv1.Launcher_WatchDomainEventsClient, error) {
+	return nil, nil
+}
+
+func (f *fakeLauncherClient) Health() HealthStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return HealthStatus{Healthy: f.healthy}
+}
+
+func (f *fakeLauncherClient) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+func (f *fakeLauncherClient) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+func (f *fakeLauncherClient) syncCallCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.syncCalls
+}
@@ -0,0 +1,68 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"time"
+)
+
+// HealthStatus is a point-in-time snapshot of a LauncherClient's background
+// health check, read by virt-handler's reconcile loop to decide whether to
+// reschedule work against a VM or wait for its connection to recover.
+type HealthStatus struct {
+	Healthy             bool
+	ConsecutiveFailures int
+	LastError           error
+	LastChecked         time.Time
+}
+
+// ClientOptions tunes the reconnect/health-check behavior of a
+// VirtLauncherClient. The zero value is not valid; use
+// DefaultClientOptions.
+type ClientOptions struct {
+	// CallTimeout bounds any method call that doesn't already carry a
+	// context deadline.
+	CallTimeout time.Duration
+	// PingInterval is how often the background health check calls Ping.
+	PingInterval time.Duration
+	// MaxConsecutiveFailures is how many Ping failures in a row mark the
+	// client unhealthy, causing it to refuse new calls with
+	// ErrUnavailable.
+	MaxConsecutiveFailures int
+	// InitialReconnectBackoff and MaxReconnectBackoff bound the
+	// exponential backoff used to re-dial the socket after a disconnect.
+	InitialReconnectBackoff time.Duration
+	MaxReconnectBackoff     time.Duration
+	// MaxReconnectAttempts caps how many times reconnect backs off before
+	// giving up and surfacing the dial error to the caller.
+	MaxReconnectAttempts int
+}
+
+// DefaultClientOptions returns the settings GetClient uses in production.
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		CallTimeout:             10 * time.Second,
+		PingInterval:            5 * time.Second,
+		MaxConsecutiveFailures:  3,
+		InitialReconnectBackoff: 100 * time.Millisecond,
+		MaxReconnectBackoff:     10 * time.Second,
+		MaxReconnectAttempts:    5,
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %s", timeout)
+}
+
+func TestHealthLoopMarksUnhealthyThenRecovers(t *testing.T) {
+	srv := &fakeServer{pingFailuresLeft: 5}
+	transport := NewInProcessTransport(srv)
+	defer transport.Close()
+
+	opts := DefaultClientOptions()
+	opts.PingInterval = 10 * time.Millisecond
+	opts.MaxConsecutiveFailures = 2
+
+	client, err := NewClient(transport, "ignored", opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	waitFor(t, 2*time.Second, func() bool { return !client.Health().Healthy })
+
+	if err := client.Ping(context.Background()); !IsUnavailable(err) {
+		t.Fatalf("expected ErrUnavailable once unhealthy, got %v", err)
+	}
+
+	waitFor(t, 2*time.Second, func() bool { return client.Health().Healthy })
+}
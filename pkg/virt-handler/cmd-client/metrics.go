@@ -0,0 +1,45 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	rpcInflight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "virt_handler_launcher_rpc_inflight",
+		Help: "Number of LauncherClient RPCs currently in flight to virt-launcher sockets.",
+	})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "virt_handler_launcher_rpc_duration_seconds",
+		Help: "Duration of LauncherClient RPCs to virt-launcher sockets, by command.",
+	}, []string{"command"})
+
+	rpcReconnectsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "virt_handler_launcher_rpc_reconnects_total",
+		Help: "Total number of times a LauncherClient re-dialed a virt-launcher socket after a disconnect.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(rpcInflight, rpcDuration, rpcReconnectsTotal)
+}
@@ -0,0 +1,147 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+//go:generate mockgen -source $GOFILE -package=$GOPACKAGE -destination=generated_mock_$GOFILE
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	cmdv1 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
+)
+
+// bufconnBufSize is generous enough for domain sync payloads in tests
+// without requiring a real socket buffer to be sized.
+const bufconnBufSize = 1024 * 1024
+
+// RPCConn is the subset of *grpc.ClientConn that LauncherClient needs. It
+// lets Transport implementations hand back anything gRPC can dial a stream
+// over, including in-memory pipes used by tests.
+type RPCConn interface {
+	grpc.ClientConnInterface
+	Close() error
+}
+
+// Transport dials the connection a LauncherClient speaks gRPC over. Passing
+// a different Transport to NewClient lets the same call sites run against a
+// real virt-launcher Unix socket, an in-process test double, or a socket on
+// a remote node reached through an SSH bastion, mirroring the CommandRunner
+// pattern used for other node-local operations in the Kubernetes ecosystem.
+type Transport interface {
+	Dial(ctx context.Context, target string) (RPCConn, error)
+	Close()
+}
+
+// UnixSocketTransport dials a virt-launcher Unix socket directly on the
+// local node. This is what GetClient uses in production.
+type UnixSocketTransport struct{}
+
+func NewUnixSocketTransport() Transport {
+	return &UnixSocketTransport{}
+}
+
+func (t *UnixSocketTransport) Dial(ctx context.Context, target string) (RPCConn, error) {
+	return grpc.DialContext(ctx, target,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}),
+	)
+}
+
+func (t *UnixSocketTransport) Close() {}
+
+// InProcessTransport shortcuts the dial to an in-memory pipe connected to a
+// LauncherServer running in the same process. It exists so
+// pkg/virt-handler tests can exercise LauncherClient without a real
+// virt-launcher pod or Unix socket.
+type InProcessTransport struct {
+	listener *bufconn.Listener
+	server   *grpc.Server
+}
+
+// NewInProcessTransport starts srv on an in-memory listener and returns a
+// Transport whose Dial always connects to it, regardless of target.
+func NewInProcessTransport(srv cmdv1.LauncherServer) *InProcessTransport {
+	listener := bufconn.Listen(bufconnBufSize)
+
+	grpcServer := grpc.NewServer()
+	cmdv1.RegisterLauncherServer(grpcServer, srv)
+	go grpcServer.Serve(listener)
+
+	return &InProcessTransport{
+		listener: listener,
+		server:   grpcServer,
+	}
+}
+
+func (t *InProcessTransport) Dial(ctx context.Context, target string) (RPCConn, error) {
+	return grpc.DialContext(ctx, "bufconn",
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return t.listener.Dial()
+		}),
+	)
+}
+
+func (t *InProcessTransport) Close() {
+	t.server.Stop()
+	t.listener.Close()
+}
+
+// SSHTransport tunnels the gRPC connection through an SSH bastion so
+// `virtctl launcher-debug --node <node>` can dial a virt-launcher pod's
+// socket from an operator's workstation.
+type SSHTransport struct {
+	client *ssh.Client
+}
+
+// NewSSHTransport opens an SSH connection to addr (typically a node
+// reachable through a bastion) and returns a Transport that forwards gRPC
+// dials over it to the remote Unix socket given as target.
+func NewSSHTransport(addr string, config *ssh.ClientConfig) (*SSHTransport, error) {
+	client, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, err
+	}
+	return &SSHTransport{client: client}, nil
+}
+
+func (t *SSHTransport) Dial(ctx context.Context, target string) (RPCConn, error) {
+	return grpc.DialContext(ctx, target,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, socketPath string) (net.Conn, error) {
+			return t.client.Dial("unix", socketPath)
+		}),
+	)
+}
+
+func (t *SSHTransport) Close() {
+	t.client.Close()
+}
@@ -0,0 +1,63 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2018 Red Hat, Inc.
+ *
+ */
+
+package cmdclient
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInProcessTransportRoundTripsPing(t *testing.T) {
+	transport := NewInProcessTransport(&fakeServer{})
+	defer transport.Close()
+
+	client, err := NewClient(transport, "ignored", DefaultClientOptions())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping over InProcessTransport failed: %v", err)
+	}
+}
+
+func TestMemoryDiscovery(t *testing.T) {
+	d := NewMemoryDiscovery()
+	d.Sockets["/var/run/kubevirt"] = []string{"/var/run/kubevirt/sockets/default_vm1_sock"}
+
+	sockets, err := d.ListAllSockets("/var/run/kubevirt")
+	if err != nil {
+		t.Fatalf("ListAllSockets failed: %v", err)
+	}
+	if len(sockets) != 1 || sockets[0] != "/var/run/kubevirt/sockets/default_vm1_sock" {
+		t.Fatalf("unexpected sockets returned: %v", sockets)
+	}
+
+	if sockets, err := d.ListAllSockets("/does/not/exist"); err != nil || len(sockets) != 0 {
+		t.Fatalf("expected no sockets for an unregistered baseDir, got %v, %v", sockets, err)
+	}
+
+	got := d.SocketFromNamespaceName("/var/run/kubevirt", "default", "vm1")
+	want := "/var/run/kubevirt/sockets/default_vm1_sock"
+	if got != want {
+		t.Fatalf("SocketFromNamespaceName() = %q, want %q", got, want)
+	}
+}